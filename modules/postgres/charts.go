@@ -42,6 +42,38 @@ const (
 	prioDBTempFiles
 	prioDBTempFilesData
 	prioDBSize
+
+	prioTableSeqScan
+	prioTableIdxScan
+	prioTableTuples
+	prioTableHOTUpdates
+	prioTableVacuumAge
+	prioTableIO
+	prioTableBloatEstimate
+
+	prioIndexScan
+	prioIndexIO
+
+	prioReplicationLagBytes
+	prioReplicationLagTime
+	prioReplicationSlotRetained
+	prioReplicationSlotActive
+
+	prioStatementTotalTime
+	prioStatementMeanTime
+	prioStatementCalls
+	prioStatementRows
+	prioStatementSharedBlks
+
+	prioActivityStates
+	prioActivityWaitEvents
+	prioActivityXactAge
+	prioActivityIdleInXactAge
+
+	prioDBActivityStates
+	prioDBActivityWaitEvents
+	prioDBActivityXactAge
+	prioDBActivityIdleInXactAge
 )
 
 var baseCharts = module.Charts{
@@ -64,6 +96,11 @@ var baseCharts = module.Charts{
 	catalogRelationCountChart.Copy(),
 	catalogRelationSizeChart.Copy(),
 	serverUptimeChart.Copy(),
+
+	activityStatesChart.Copy(),
+	activityWaitEventsChart.Copy(),
+	activityXactAgeChart.Copy(),
+	activityIdleInXactAgeChart.Copy(),
 }
 
 var (
@@ -310,6 +347,66 @@ var (
 			{ID: "server_uptime", Name: "uptime"},
 		},
 	}
+
+	activityStatesChart = module.Chart{
+		ID:       "activity_states",
+		Title:    "Connections in each state",
+		Units:    "connections",
+		Fam:      "activity",
+		Ctx:      "postgres.activity_states",
+		Priority: prioActivityStates,
+		Type:     module.Stacked,
+		Dims: module.Dims{
+			{ID: "activity_state_active", Name: "active"},
+			{ID: "activity_state_idle", Name: "idle"},
+			{ID: "activity_state_idle_in_transaction", Name: "idle_in_transaction"},
+			{ID: "activity_state_idle_in_transaction_aborted", Name: "idle_in_transaction_aborted"},
+			{ID: "activity_state_fastpath_function_call", Name: "fastpath_function_call"},
+			{ID: "activity_state_disabled", Name: "disabled"},
+		},
+	}
+	activityWaitEventsChart = module.Chart{
+		ID:       "activity_wait_events",
+		Title:    "Connections waiting by wait event type",
+		Units:    "connections",
+		Fam:      "activity",
+		Ctx:      "postgres.activity_wait_events",
+		Priority: prioActivityWaitEvents,
+		Type:     module.Stacked,
+		Dims: module.Dims{
+			{ID: "activity_wait_event_type_lwlock", Name: "lwlock"},
+			{ID: "activity_wait_event_type_lock", Name: "lock"},
+			{ID: "activity_wait_event_type_bufferpin", Name: "bufferpin"},
+			{ID: "activity_wait_event_type_activity", Name: "activity"},
+			{ID: "activity_wait_event_type_client", Name: "client"},
+			{ID: "activity_wait_event_type_io", Name: "io"},
+			{ID: "activity_wait_event_type_ipc", Name: "ipc"},
+			{ID: "activity_wait_event_type_timeout", Name: "timeout"},
+			{ID: "activity_wait_event_type_extension", Name: "extension"},
+		},
+	}
+	activityXactAgeChart = module.Chart{
+		ID:       "activity_longest_xact_age",
+		Title:    "Longest running transaction age",
+		Units:    "seconds",
+		Fam:      "activity",
+		Ctx:      "postgres.activity_longest_xact_age",
+		Priority: prioActivityXactAge,
+		Dims: module.Dims{
+			{ID: "activity_longest_xact_age", Name: "xact_age"},
+		},
+	}
+	activityIdleInXactAgeChart = module.Chart{
+		ID:       "activity_longest_idle_in_xact_age",
+		Title:    "Longest idle in transaction time",
+		Units:    "seconds",
+		Fam:      "activity",
+		Ctx:      "postgres.activity_longest_idle_in_xact_age",
+		Priority: prioActivityIdleInXactAge,
+		Dims: module.Dims{
+			{ID: "activity_longest_idle_in_xact_age", Name: "idle_in_xact_age"},
+		},
+	}
 )
 
 var (
@@ -328,6 +425,10 @@ var (
 		dbTempFilesChartTmpl.Copy(),
 		dbTempFilesDataChartTmpl.Copy(),
 		dbSizeChartTmpl.Copy(),
+		dbActivityStatesChartTmpl.Copy(),
+		dbActivityWaitEventsChartTmpl.Copy(),
+		dbActivityXactAgeChartTmpl.Copy(),
+		dbActivityIdleInXactAgeChartTmpl.Copy(),
 	}
 	dbTransactionsChartTmpl = module.Chart{
 		ID:       "db_%s_transactions",
@@ -509,8 +610,524 @@ var (
 			{ID: "db_%s_size", Name: "size"},
 		},
 	}
+	dbActivityStatesChartTmpl = module.Chart{
+		ID:       "db_%s_activity_states",
+		Title:    "Database connections in each state",
+		Units:    "connections",
+		Fam:      "db activity",
+		Ctx:      "postgres.db_activity_states",
+		Priority: prioDBActivityStates,
+		Type:     module.Stacked,
+		Dims: module.Dims{
+			{ID: "db_%s_activity_state_active", Name: "active"},
+			{ID: "db_%s_activity_state_idle", Name: "idle"},
+			{ID: "db_%s_activity_state_idle_in_transaction", Name: "idle_in_transaction"},
+			{ID: "db_%s_activity_state_idle_in_transaction_aborted", Name: "idle_in_transaction_aborted"},
+			{ID: "db_%s_activity_state_fastpath_function_call", Name: "fastpath_function_call"},
+			{ID: "db_%s_activity_state_disabled", Name: "disabled"},
+		},
+	}
+	dbActivityWaitEventsChartTmpl = module.Chart{
+		ID:       "db_%s_activity_wait_events",
+		Title:    "Database connections waiting by wait event type",
+		Units:    "connections",
+		Fam:      "db activity",
+		Ctx:      "postgres.db_activity_wait_events",
+		Priority: prioDBActivityWaitEvents,
+		Type:     module.Stacked,
+		Dims: module.Dims{
+			{ID: "db_%s_activity_wait_event_type_lwlock", Name: "lwlock"},
+			{ID: "db_%s_activity_wait_event_type_lock", Name: "lock"},
+			{ID: "db_%s_activity_wait_event_type_bufferpin", Name: "bufferpin"},
+			{ID: "db_%s_activity_wait_event_type_activity", Name: "activity"},
+			{ID: "db_%s_activity_wait_event_type_client", Name: "client"},
+			{ID: "db_%s_activity_wait_event_type_io", Name: "io"},
+			{ID: "db_%s_activity_wait_event_type_ipc", Name: "ipc"},
+			{ID: "db_%s_activity_wait_event_type_timeout", Name: "timeout"},
+			{ID: "db_%s_activity_wait_event_type_extension", Name: "extension"},
+		},
+	}
+	dbActivityXactAgeChartTmpl = module.Chart{
+		ID:       "db_%s_activity_longest_xact_age",
+		Title:    "Database longest running transaction age",
+		Units:    "seconds",
+		Fam:      "db activity",
+		Ctx:      "postgres.db_activity_longest_xact_age",
+		Priority: prioDBActivityXactAge,
+		Dims: module.Dims{
+			{ID: "db_%s_activity_longest_xact_age", Name: "xact_age"},
+		},
+	}
+	dbActivityIdleInXactAgeChartTmpl = module.Chart{
+		ID:       "db_%s_activity_longest_idle_in_xact_age",
+		Title:    "Database longest idle in transaction time",
+		Units:    "seconds",
+		Fam:      "db activity",
+		Ctx:      "postgres.db_activity_longest_idle_in_xact_age",
+		Priority: prioDBActivityIdleInXactAge,
+		Dims: module.Dims{
+			{ID: "db_%s_activity_longest_idle_in_xact_age", Name: "idle_in_xact_age"},
+		},
+	}
+)
+
+var (
+	// tableChartsTmpl are the charts every collected table gets.
+	// tableBloatEstimateChartTmpl is intentionally not part of this set: it is
+	// appended by newTableCharts only when Config.CollectBloatSize is on,
+	// since the bloat query is expensive to run on every table.
+	tableChartsTmpl = module.Charts{
+		tableSeqScanChartTmpl.Copy(),
+		tableIdxScanChartTmpl.Copy(),
+		tableTuplesChartTmpl.Copy(),
+		tableHOTUpdatesChartTmpl.Copy(),
+		tableVacuumAgeChartTmpl.Copy(),
+		tableIOChartTmpl.Copy(),
+	}
+	tableSeqScanChartTmpl = module.Chart{
+		ID:       "db_%s_schema_%s_table_%s_seq_scans",
+		Title:    "Table sequential scans",
+		Units:    "scans/s",
+		Fam:      "table statistics",
+		Ctx:      "postgres.table_seq_scans",
+		Priority: prioTableSeqScan,
+		Dims: module.Dims{
+			{ID: "db_%s_schema_%s_table_%s_seq_scan", Name: "sequential", Algo: module.Incremental},
+		},
+	}
+	tableIdxScanChartTmpl = module.Chart{
+		ID:       "db_%s_schema_%s_table_%s_idx_scans",
+		Title:    "Table index scans",
+		Units:    "scans/s",
+		Fam:      "table statistics",
+		Ctx:      "postgres.table_idx_scans",
+		Priority: prioTableIdxScan,
+		Dims: module.Dims{
+			{ID: "db_%s_schema_%s_table_%s_idx_scan", Name: "index", Algo: module.Incremental},
+		},
+	}
+	tableTuplesChartTmpl = module.Chart{
+		ID:       "db_%s_schema_%s_table_%s_tuples",
+		Title:    "Table live and dead tuples",
+		Units:    "tuples",
+		Fam:      "table statistics",
+		Ctx:      "postgres.table_tuples",
+		Priority: prioTableTuples,
+		Type:     module.Stacked,
+		Dims: module.Dims{
+			{ID: "db_%s_schema_%s_table_%s_n_live_tup", Name: "live"},
+			{ID: "db_%s_schema_%s_table_%s_n_dead_tup", Name: "dead"},
+		},
+	}
+	tableHOTUpdatesChartTmpl = module.Chart{
+		ID:       "db_%s_schema_%s_table_%s_hot_updates",
+		Title:    "Table tuples HOT updated",
+		Units:    "percentage",
+		Fam:      "table statistics",
+		Ctx:      "postgres.table_hot_updates",
+		Priority: prioTableHOTUpdates,
+		Dims: module.Dims{
+			{ID: "db_%s_schema_%s_table_%s_n_tup_hot_upd_perc", Name: "hot"},
+		},
+	}
+	tableVacuumAgeChartTmpl = module.Chart{
+		ID:       "db_%s_schema_%s_table_%s_last_autovacuum_since",
+		Title:    "Table time since last autovacuum",
+		Units:    "seconds",
+		Fam:      "table statistics",
+		Ctx:      "postgres.table_last_autovacuum_since",
+		Priority: prioTableVacuumAge,
+		Dims: module.Dims{
+			{ID: "db_%s_schema_%s_table_%s_last_autovacuum_ago", Name: "time"},
+		},
+	}
+	tableIOChartTmpl = module.Chart{
+		ID:       "db_%s_schema_%s_table_%s_io",
+		Title:    "Table I/O",
+		Units:    "blocks/s",
+		Fam:      "table statistics",
+		Ctx:      "postgres.table_io",
+		Priority: prioTableIO,
+		Type:     module.Area,
+		Dims: module.Dims{
+			{ID: "db_%s_schema_%s_table_%s_heap_blks_hit", Name: "hit", Algo: module.Incremental},
+			{ID: "db_%s_schema_%s_table_%s_heap_blks_read", Name: "miss", Algo: module.Incremental},
+		},
+	}
+	tableBloatEstimateChartTmpl = module.Chart{
+		ID:       "db_%s_schema_%s_table_%s_bloat_size_perc",
+		Title:    "Table bloat size percentage",
+		Units:    "percentage",
+		Fam:      "table statistics",
+		Ctx:      "postgres.table_bloat_size_perc",
+		Priority: prioTableBloatEstimate,
+		Dims: module.Dims{
+			{ID: "db_%s_schema_%s_table_%s_bloat_size_perc", Name: "bloat"},
+		},
+	}
+)
+
+var (
+	indexChartsTmpl = module.Charts{
+		indexScanChartTmpl.Copy(),
+		indexIOChartTmpl.Copy(),
+	}
+	indexScanChartTmpl = module.Chart{
+		ID:       "db_%s_schema_%s_table_%s_index_%s_scans",
+		Title:    "Index scans",
+		Units:    "scans/s",
+		Fam:      "index statistics",
+		Ctx:      "postgres.index_scans",
+		Priority: prioIndexScan,
+		Dims: module.Dims{
+			{ID: "db_%s_schema_%s_table_%s_index_%s_idx_scan", Name: "scans", Algo: module.Incremental},
+		},
+	}
+	indexIOChartTmpl = module.Chart{
+		ID:       "db_%s_schema_%s_table_%s_index_%s_io",
+		Title:    "Index I/O",
+		Units:    "blocks/s",
+		Fam:      "index statistics",
+		Ctx:      "postgres.index_io",
+		Priority: prioIndexIO,
+		Type:     module.Area,
+		Dims: module.Dims{
+			{ID: "db_%s_schema_%s_table_%s_index_%s_idx_blks_hit", Name: "hit", Algo: module.Incremental},
+			{ID: "db_%s_schema_%s_table_%s_index_%s_idx_blks_read", Name: "miss", Algo: module.Incremental},
+		},
+	}
 )
 
+func newTableCharts(dbname, schema, table string, withBloat bool) *module.Charts {
+	tmpl := tableChartsTmpl.Copy()
+	if withBloat {
+		*tmpl = append(*tmpl, tableBloatEstimateChartTmpl.Copy())
+	}
+	for _, c := range *tmpl {
+		c.ID = fmt.Sprintf(c.ID, dbname, schema, table)
+		c.Labels = []module.Label{
+			{Key: "database", Value: dbname},
+			{Key: "schema", Value: schema},
+			{Key: "table", Value: table},
+		}
+		for _, d := range c.Dims {
+			d.ID = fmt.Sprintf(d.ID, dbname, schema, table)
+		}
+	}
+	return tmpl
+}
+
+func (p *Postgres) addNewTableCharts(dbname, schema, table string) {
+	charts := newTableCharts(dbname, schema, table, p.CollectBloatSize)
+	if err := p.Charts().Add(*charts...); err != nil {
+		p.Warning(err)
+	}
+}
+
+// removeTableCharts removes the table-tier charts for (dbname, schema, table).
+// It matches on the chart's database/schema/table labels rather than an ID
+// prefix: table names routinely share a prefix (e.g. "users", "users_archive"),
+// and "db_x_schema_public_table_users_" is itself a prefix of
+// "db_x_schema_public_table_users_archive_seq_scans".
+func (p *Postgres) removeTableCharts(dbname, schema, table string) {
+	want := map[string]string{"database": dbname, "schema": schema, "table": table}
+	for _, c := range *p.Charts() {
+		if chartHasLabel(c, "index") {
+			continue // index-tier chart of this table, removed by removeIndexCharts
+		}
+		if chartMatchesLabels(c, want) {
+			c.MarkRemove()
+			c.MarkNotCreated()
+		}
+	}
+}
+
+func newIndexCharts(dbname, schema, table, index string) *module.Charts {
+	charts := indexChartsTmpl.Copy()
+	for _, c := range *charts {
+		c.ID = fmt.Sprintf(c.ID, dbname, schema, table, index)
+		c.Labels = []module.Label{
+			{Key: "database", Value: dbname},
+			{Key: "schema", Value: schema},
+			{Key: "table", Value: table},
+			{Key: "index", Value: index},
+		}
+		for _, d := range c.Dims {
+			d.ID = fmt.Sprintf(d.ID, dbname, schema, table, index)
+		}
+	}
+	return charts
+}
+
+func (p *Postgres) addNewIndexCharts(dbname, schema, table, index string) {
+	charts := newIndexCharts(dbname, schema, table, index)
+	if err := p.Charts().Add(*charts...); err != nil {
+		p.Warning(err)
+	}
+}
+
+func (p *Postgres) removeIndexCharts(dbname, schema, table, index string) {
+	want := map[string]string{"database": dbname, "schema": schema, "table": table, "index": index}
+	for _, c := range *p.Charts() {
+		if chartMatchesLabels(c, want) {
+			c.MarkRemove()
+			c.MarkNotCreated()
+		}
+	}
+}
+
+func chartHasLabel(c *module.Chart, key string) bool {
+	for _, l := range c.Labels {
+		if l.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func chartMatchesLabels(c *module.Chart, want map[string]string) bool {
+	if len(c.Labels) == 0 {
+		return false
+	}
+	have := make(map[string]string, len(c.Labels))
+	for _, l := range c.Labels {
+		have[l.Key] = l.Value
+	}
+	for k, v := range want {
+		if have[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+var (
+	replicationStandbyChartsTmpl = module.Charts{
+		replicationStandbyLagBytesChartTmpl.Copy(),
+		replicationStandbyLagTimeChartTmpl.Copy(),
+	}
+	replicationStandbyLagBytesChartTmpl = module.Chart{
+		ID:       "replication_standby_app_%s_lag_bytes",
+		Title:    "Standby replication lag",
+		Units:    "B",
+		Fam:      "replication",
+		Ctx:      "postgres.replication_standby_app_lag_bytes",
+		Priority: prioReplicationLagBytes,
+		Dims: module.Dims{
+			{ID: "replication_standby_app_%s_sent_lag_bytes", Name: "sent_lag"},
+			{ID: "replication_standby_app_%s_write_lag_bytes", Name: "write_lag"},
+			{ID: "replication_standby_app_%s_flush_lag_bytes", Name: "flush_lag"},
+			{ID: "replication_standby_app_%s_replay_lag_bytes", Name: "replay_lag"},
+		},
+	}
+	replicationStandbyLagTimeChartTmpl = module.Chart{
+		ID:       "replication_standby_app_%s_lag_time",
+		Title:    "Standby replication lag time",
+		Units:    "milliseconds",
+		Fam:      "replication",
+		Ctx:      "postgres.replication_standby_app_lag_time",
+		Priority: prioReplicationLagTime,
+		Dims: module.Dims{
+			{ID: "replication_standby_app_%s_write_lag_time", Name: "write_lag"},
+			{ID: "replication_standby_app_%s_flush_lag_time", Name: "flush_lag"},
+			{ID: "replication_standby_app_%s_replay_lag_time", Name: "replay_lag"},
+		},
+	}
+)
+
+var (
+	replicationSlotChartsTmpl = module.Charts{
+		replicationSlotRetainedWALChartTmpl.Copy(),
+		replicationSlotActiveChartTmpl.Copy(),
+	}
+	replicationSlotRetainedWALChartTmpl = module.Chart{
+		ID:       "replication_slot_%s_retained_wal",
+		Title:    "Replication slot retained WAL",
+		Units:    "B",
+		Fam:      "replication",
+		Ctx:      "postgres.replication_slot_retained_wal",
+		Priority: prioReplicationSlotRetained,
+		Dims: module.Dims{
+			{ID: "replication_slot_%s_restart_lsn_retained_bytes", Name: "restart_lsn"},
+			{ID: "replication_slot_%s_confirmed_flush_lsn_retained_bytes", Name: "confirmed_flush_lsn"},
+		},
+	}
+	replicationSlotActiveChartTmpl = module.Chart{
+		ID:       "replication_slot_%s_active",
+		Title:    "Replication slot active state",
+		Units:    "state",
+		Fam:      "replication",
+		Ctx:      "postgres.replication_slot_active",
+		Priority: prioReplicationSlotActive,
+		Dims: module.Dims{
+			{ID: "replication_slot_%s_active", Name: "active"},
+		},
+	}
+)
+
+func newReplicationStandbyCharts(appName string) *module.Charts {
+	charts := replicationStandbyChartsTmpl.Copy()
+	for _, c := range *charts {
+		c.ID = fmt.Sprintf(c.ID, appName)
+		c.Labels = []module.Label{
+			{Key: "application_name", Value: appName},
+		}
+		for _, d := range c.Dims {
+			d.ID = fmt.Sprintf(d.ID, appName)
+		}
+	}
+	return charts
+}
+
+func (p *Postgres) addNewReplicationStandbyCharts(appName string) {
+	charts := newReplicationStandbyCharts(appName)
+	if err := p.Charts().Add(*charts...); err != nil {
+		p.Warning(err)
+	}
+}
+
+func (p *Postgres) removeReplicationStandbyCharts(appName string) {
+	prefix := fmt.Sprintf("replication_standby_app_%s_", appName)
+	for _, c := range *p.Charts() {
+		if strings.HasPrefix(c.ID, prefix) {
+			c.MarkRemove()
+			c.MarkNotCreated()
+		}
+	}
+}
+
+func newReplicationSlotCharts(slotName string) *module.Charts {
+	charts := replicationSlotChartsTmpl.Copy()
+	for _, c := range *charts {
+		c.ID = fmt.Sprintf(c.ID, slotName)
+		c.Labels = []module.Label{
+			{Key: "slot_name", Value: slotName},
+		}
+		for _, d := range c.Dims {
+			d.ID = fmt.Sprintf(d.ID, slotName)
+		}
+	}
+	return charts
+}
+
+func (p *Postgres) addNewReplicationSlotCharts(slotName string) {
+	charts := newReplicationSlotCharts(slotName)
+	if err := p.Charts().Add(*charts...); err != nil {
+		p.Warning(err)
+	}
+}
+
+func (p *Postgres) removeReplicationSlotCharts(slotName string) {
+	prefix := fmt.Sprintf("replication_slot_%s_", slotName)
+	for _, c := range *p.Charts() {
+		if strings.HasPrefix(c.ID, prefix) {
+			c.MarkRemove()
+			c.MarkNotCreated()
+		}
+	}
+}
+
+var (
+	statementChartsTmpl = module.Charts{
+		statementTotalTimeChartTmpl.Copy(),
+		statementMeanTimeChartTmpl.Copy(),
+		statementCallsChartTmpl.Copy(),
+		statementRowsChartTmpl.Copy(),
+		statementSharedBlksChartTmpl.Copy(),
+	}
+	statementTotalTimeChartTmpl = module.Chart{
+		ID:       "statement_%s_total_time",
+		Title:    "Statement total time",
+		Units:    "milliseconds",
+		Fam:      "statements",
+		Ctx:      "postgres.statement_total_time",
+		Priority: prioStatementTotalTime,
+		Dims: module.Dims{
+			{ID: "statement_%s_total_time", Name: "total_time"},
+		},
+	}
+	statementMeanTimeChartTmpl = module.Chart{
+		ID:       "statement_%s_mean_time",
+		Title:    "Statement mean time",
+		Units:    "milliseconds",
+		Fam:      "statements",
+		Ctx:      "postgres.statement_mean_time",
+		Priority: prioStatementMeanTime,
+		Dims: module.Dims{
+			{ID: "statement_%s_mean_time", Name: "mean_time"},
+		},
+	}
+	statementCallsChartTmpl = module.Chart{
+		ID:       "statement_%s_calls",
+		Title:    "Statement calls",
+		Units:    "calls/s",
+		Fam:      "statements",
+		Ctx:      "postgres.statement_calls",
+		Priority: prioStatementCalls,
+		Dims: module.Dims{
+			{ID: "statement_%s_calls", Name: "calls", Algo: module.Incremental},
+		},
+	}
+	statementRowsChartTmpl = module.Chart{
+		ID:       "statement_%s_rows",
+		Title:    "Statement rows returned",
+		Units:    "rows/s",
+		Fam:      "statements",
+		Ctx:      "postgres.statement_rows",
+		Priority: prioStatementRows,
+		Dims: module.Dims{
+			{ID: "statement_%s_rows", Name: "rows", Algo: module.Incremental},
+		},
+	}
+	statementSharedBlksChartTmpl = module.Chart{
+		ID:       "statement_%s_shared_blks",
+		Title:    "Statement shared block hits and reads",
+		Units:    "blocks/s",
+		Fam:      "statements",
+		Ctx:      "postgres.statement_shared_blks",
+		Priority: prioStatementSharedBlks,
+		Type:     module.Area,
+		Dims: module.Dims{
+			{ID: "statement_%s_shared_blks_hit", Name: "hit", Algo: module.Incremental},
+			{ID: "statement_%s_shared_blks_read", Name: "read", Algo: module.Incremental},
+		},
+	}
+)
+
+func newStatementCharts(queryID, userID, dbID, query string) *module.Charts {
+	charts := statementChartsTmpl.Copy()
+	for _, c := range *charts {
+		c.ID = fmt.Sprintf(c.ID, queryID)
+		c.Labels = []module.Label{
+			{Key: "queryid", Value: queryID},
+			{Key: "userid", Value: userID},
+			{Key: "dbid", Value: dbID},
+			{Key: "query", Value: query},
+		}
+		for _, d := range c.Dims {
+			d.ID = fmt.Sprintf(d.ID, queryID)
+		}
+	}
+	return charts
+}
+
+func (p *Postgres) addNewStatementCharts(queryID, userID, dbID, query string) {
+	charts := newStatementCharts(queryID, userID, dbID, query)
+	if err := p.Charts().Add(*charts...); err != nil {
+		p.Warning(err)
+	}
+}
+
+func (p *Postgres) removeStatementCharts(queryID string) {
+	prefix := fmt.Sprintf("statement_%s_", queryID)
+	for _, c := range *p.Charts() {
+		if strings.HasPrefix(c.ID, prefix) {
+			c.MarkRemove()
+			c.MarkNotCreated()
+		}
+	}
+}
+
 func newDatabaseCharts(dbname string) *module.Charts {
 	charts := dbChartsTmpl.Copy()
 	for _, c := range *charts {
@@ -540,4 +1157,4 @@ func (p *Postgres) removeDatabaseCharts(dbname string) {
 			c.MarkNotCreated()
 		}
 	}
-}
\ No newline at end of file
+}
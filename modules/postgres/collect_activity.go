@@ -0,0 +1,263 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package postgres
+
+var activityStateKeys = map[string]string{
+	"active":                        "active",
+	"idle":                          "idle",
+	"idle in transaction":           "idle_in_transaction",
+	"idle in transaction (aborted)": "idle_in_transaction_aborted",
+	"fastpath function call":        "fastpath_function_call",
+	"disabled":                      "disabled",
+}
+
+var activityWaitEventTypeKeys = map[string]string{
+	"LWLock":    "lwlock",
+	"Lock":      "lock",
+	"BufferPin": "bufferpin",
+	"Activity":  "activity",
+	"Client":    "client",
+	"IO":        "io",
+	"IPC":       "ipc",
+	"Timeout":   "timeout",
+	"Extension": "extension",
+}
+
+func activityStateKey(state string) (string, bool) {
+	k, ok := activityStateKeys[state]
+	return k, ok
+}
+
+func activityWaitEventTypeKey(eventType string) (string, bool) {
+	k, ok := activityWaitEventTypeKeys[eventType]
+	return k, ok
+}
+
+// activityExcludeArgs turns an empty exclude pattern into one that matches
+// nothing, so the `application_name !~ $1` filter is always safe to apply.
+func activityExcludeArgs(excludeAppName string) []any {
+	if excludeAppName == "" {
+		excludeAppName = "$^"
+	}
+	return []any{excludeAppName}
+}
+
+const queryServerActivityStates = `
+SELECT state, COUNT(*) AS connections
+FROM pg_stat_activity
+WHERE state IS NOT NULL AND application_name !~ $1
+GROUP BY state
+`
+
+const queryServerActivityWaitEvents = `
+SELECT wait_event_type, COUNT(*) AS connections
+FROM pg_stat_activity
+WHERE wait_event_type IS NOT NULL AND application_name !~ $1
+GROUP BY wait_event_type
+`
+
+const queryServerActivityXactAge = `
+SELECT
+    COALESCE(MAX(EXTRACT(EPOCH FROM (now() - xact_start))) FILTER (WHERE state != 'idle'), 0)                 AS longest_xact_age_seconds,
+    COALESCE(MAX(EXTRACT(EPOCH FROM (now() - state_change))) FILTER (WHERE state = 'idle in transaction'), 0) AS longest_idle_in_xact_age_seconds
+FROM pg_stat_activity
+WHERE application_name !~ $1
+`
+
+const queryDatabaseActivityDatabases = `
+SELECT DISTINCT datname
+FROM pg_stat_activity
+WHERE datname IS NOT NULL AND application_name !~ $1
+`
+
+const queryDatabaseActivityStates = `
+SELECT datname, state, COUNT(*) AS connections
+FROM pg_stat_activity
+WHERE datname IS NOT NULL AND state IS NOT NULL AND application_name !~ $1
+GROUP BY datname, state
+`
+
+const queryDatabaseActivityWaitEvents = `
+SELECT datname, wait_event_type, COUNT(*) AS connections
+FROM pg_stat_activity
+WHERE datname IS NOT NULL AND wait_event_type IS NOT NULL AND application_name !~ $1
+GROUP BY datname, wait_event_type
+`
+
+const queryDatabaseActivityXactAge = `
+SELECT
+    datname,
+    COALESCE(MAX(EXTRACT(EPOCH FROM (now() - xact_start))) FILTER (WHERE state != 'idle'), 0)                 AS longest_xact_age_seconds,
+    COALESCE(MAX(EXTRACT(EPOCH FROM (now() - state_change))) FILTER (WHERE state = 'idle in transaction'), 0) AS longest_idle_in_xact_age_seconds
+FROM pg_stat_activity
+WHERE datname IS NOT NULL AND application_name !~ $1
+GROUP BY datname
+`
+
+func (p *Postgres) collectActivity() error {
+	args := activityExcludeArgs(p.ActivityExcludeAppName)
+
+	if err := p.collectServerActivityStates(args); err != nil {
+		return err
+	}
+	if err := p.collectServerActivityWaitEvents(args); err != nil {
+		return err
+	}
+	if err := p.collectServerActivityXactAge(args); err != nil {
+		return err
+	}
+	return p.collectDatabaseActivity(args)
+}
+
+func (p *Postgres) collectServerActivityStates(args []any) error {
+	for _, k := range activityStateKeys {
+		p.mx["activity_state_"+k] = 0
+	}
+
+	var state string
+	var connections int64
+	return p.doQuery(p.ctx, queryServerActivityStates, args, func(column, value string, rowEnd bool) {
+		switch column {
+		case "state":
+			state = value
+		case "connections":
+			connections = parseInt(value)
+		}
+		if !rowEnd {
+			return
+		}
+		if key, ok := activityStateKey(state); ok {
+			p.mx["activity_state_"+key] = connections
+		}
+	})
+}
+
+func (p *Postgres) collectServerActivityWaitEvents(args []any) error {
+	for _, k := range activityWaitEventTypeKeys {
+		p.mx["activity_wait_event_type_"+k] = 0
+	}
+
+	var eventType string
+	var connections int64
+	return p.doQuery(p.ctx, queryServerActivityWaitEvents, args, func(column, value string, rowEnd bool) {
+		switch column {
+		case "wait_event_type":
+			eventType = value
+		case "connections":
+			connections = parseInt(value)
+		}
+		if !rowEnd {
+			return
+		}
+		if key, ok := activityWaitEventTypeKey(eventType); ok {
+			p.mx["activity_wait_event_type_"+key] = connections
+		}
+	})
+}
+
+func (p *Postgres) collectServerActivityXactAge(args []any) error {
+	return p.doQuery(p.ctx, queryServerActivityXactAge, args, func(column, value string, rowEnd bool) {
+		switch column {
+		case "longest_xact_age_seconds":
+			p.mx["activity_longest_xact_age"] = parseInt(value)
+		case "longest_idle_in_xact_age_seconds":
+			p.mx["activity_longest_idle_in_xact_age"] = parseInt(value)
+		}
+	})
+}
+
+func (p *Postgres) collectDatabaseActivity(args []any) error {
+	seen := make(map[string]bool)
+
+	var datname string
+	err := p.doQuery(p.ctx, queryDatabaseActivityDatabases, args, func(column, value string, rowEnd bool) {
+		switch column {
+		case "datname":
+			datname = value
+		}
+		if !rowEnd {
+			return
+		}
+
+		seen[datname] = true
+		if !p.seenDatabases[datname] {
+			p.addNewDatabaseCharts(datname)
+		}
+		for _, k := range activityStateKeys {
+			p.mx["db_"+datname+"_activity_state_"+k] = 0
+		}
+		for _, k := range activityWaitEventTypeKeys {
+			p.mx["db_"+datname+"_activity_wait_event_type_"+k] = 0
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	var state string
+	var connections int64
+	err = p.doQuery(p.ctx, queryDatabaseActivityStates, args, func(column, value string, rowEnd bool) {
+		switch column {
+		case "datname":
+			datname = value
+		case "state":
+			state = value
+		case "connections":
+			connections = parseInt(value)
+		}
+		if !rowEnd {
+			return
+		}
+
+		if key, ok := activityStateKey(state); ok {
+			p.mx["db_"+datname+"_activity_state_"+key] = connections
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	var eventType string
+	err = p.doQuery(p.ctx, queryDatabaseActivityWaitEvents, args, func(column, value string, rowEnd bool) {
+		switch column {
+		case "datname":
+			datname = value
+		case "wait_event_type":
+			eventType = value
+		case "connections":
+			connections = parseInt(value)
+		}
+		if !rowEnd {
+			return
+		}
+		if key, ok := activityWaitEventTypeKey(eventType); ok {
+			p.mx["db_"+datname+"_activity_wait_event_type_"+key] = connections
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	err = p.doQuery(p.ctx, queryDatabaseActivityXactAge, args, func(column, value string, rowEnd bool) {
+		switch column {
+		case "datname":
+			datname = value
+		case "longest_xact_age_seconds":
+			p.mx["db_"+datname+"_activity_longest_xact_age"] = parseInt(value)
+		case "longest_idle_in_xact_age_seconds":
+			p.mx["db_"+datname+"_activity_longest_idle_in_xact_age"] = parseInt(value)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	for datname := range p.seenDatabases {
+		if !seen[datname] {
+			p.removeDatabaseCharts(datname)
+		}
+	}
+	p.seenDatabases = seen
+
+	return nil
+}
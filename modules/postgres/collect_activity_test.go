@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package postgres
+
+import "testing"
+
+func TestActivityStateKey(t *testing.T) {
+	tests := map[string]struct {
+		state string
+		want  string
+		ok    bool
+	}{
+		"active":       {state: "active", want: "active", ok: true},
+		"idle in xact": {state: "idle in transaction", want: "idle_in_transaction", ok: true},
+		"unknown":      {state: "some future state", want: "", ok: false},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, ok := activityStateKey(tc.state)
+			if got != tc.want || ok != tc.ok {
+				t.Errorf("activityStateKey(%q) = (%q, %v), want (%q, %v)", tc.state, got, ok, tc.want, tc.ok)
+			}
+		})
+	}
+}
+
+func TestActivityWaitEventTypeKey(t *testing.T) {
+	tests := map[string]struct {
+		eventType string
+		want      string
+		ok        bool
+	}{
+		"lwlock":  {eventType: "LWLock", want: "lwlock", ok: true},
+		"io":      {eventType: "IO", want: "io", ok: true},
+		"unknown": {eventType: "Bogus", want: "", ok: false},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, ok := activityWaitEventTypeKey(tc.eventType)
+			if got != tc.want || ok != tc.ok {
+				t.Errorf("activityWaitEventTypeKey(%q) = (%q, %v), want (%q, %v)", tc.eventType, got, ok, tc.want, tc.ok)
+			}
+		})
+	}
+}
+
+func TestActivityExcludeArgs(t *testing.T) {
+	if args := activityExcludeArgs(""); args[0] != "$^" {
+		t.Errorf("activityExcludeArgs(%q) = %v, want a never-matching pattern", "", args)
+	}
+	if args := activityExcludeArgs("netdata.*"); args[0] != "netdata.*" {
+		t.Errorf("activityExcludeArgs(%q) = %v, want it passed through unchanged", "netdata.*", args)
+	}
+}
@@ -0,0 +1,140 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package postgres
+
+const queryReplicationStandbyAppDelta = `
+SELECT
+    application_name,
+    pg_wal_lsn_diff(pg_current_wal_lsn(), sent_lsn) AS sent_lag_bytes,
+    pg_wal_lsn_diff(sent_lsn, write_lsn)    AS write_lag_bytes,
+    pg_wal_lsn_diff(write_lsn, flush_lsn)   AS flush_lag_bytes,
+    pg_wal_lsn_diff(flush_lsn, replay_lsn)  AS replay_lag_bytes,
+    COALESCE(EXTRACT(EPOCH FROM write_lag) * 1000, 0)  AS write_lag_time,
+    COALESCE(EXTRACT(EPOCH FROM flush_lag) * 1000, 0)  AS flush_lag_time,
+    COALESCE(EXTRACT(EPOCH FROM replay_lag) * 1000, 0) AS replay_lag_time
+FROM pg_stat_replication
+`
+
+const queryReplicationSlotFiles = `
+SELECT
+    slot_name,
+    active,
+    COALESCE(pg_wal_lsn_diff(pg_current_wal_lsn(), restart_lsn), 0)          AS restart_lsn_retained_bytes,
+    COALESCE(pg_wal_lsn_diff(pg_current_wal_lsn(), confirmed_flush_lsn), 0)  AS confirmed_flush_lsn_retained_bytes
+FROM pg_replication_slots
+`
+
+func (p *Postgres) collectReplication() error {
+	seenStandbys, err := p.collectReplicationStandbys()
+	if err != nil {
+		return err
+	}
+	seenSlots, err := p.collectReplicationSlots()
+	if err != nil {
+		return err
+	}
+
+	for appName := range p.seenStandbys {
+		if !seenStandbys[appName] {
+			p.removeReplicationStandbyCharts(appName)
+		}
+	}
+	p.seenStandbys = seenStandbys
+
+	for slotName := range p.seenSlots {
+		if !seenSlots[slotName] {
+			p.removeReplicationSlotCharts(slotName)
+		}
+	}
+	p.seenSlots = seenSlots
+
+	return nil
+}
+
+func (p *Postgres) collectReplicationStandbys() (map[string]bool, error) {
+	seen := make(map[string]bool)
+
+	var appName string
+	var sentLag, writeLagBytes, flushLagBytes, replayLagBytes, writeLagTime, flushLagTime, replayLagTime int64
+
+	err := p.doQuery(p.ctx, queryReplicationStandbyAppDelta, nil, func(column, value string, rowEnd bool) {
+		switch column {
+		case "application_name":
+			appName = value
+		case "sent_lag_bytes":
+			sentLag = parseInt(value)
+		case "write_lag_bytes":
+			writeLagBytes = parseInt(value)
+		case "flush_lag_bytes":
+			flushLagBytes = parseInt(value)
+		case "replay_lag_bytes":
+			replayLagBytes = parseInt(value)
+		case "write_lag_time":
+			writeLagTime = parseInt(value)
+		case "flush_lag_time":
+			flushLagTime = parseInt(value)
+		case "replay_lag_time":
+			replayLagTime = parseInt(value)
+		}
+		if !rowEnd {
+			return
+		}
+
+		seen[appName] = true
+		if !p.seenStandbys[appName] {
+			p.addNewReplicationStandbyCharts(appName)
+		}
+
+		px := "replication_standby_app_" + appName + "_"
+		p.mx[px+"sent_lag_bytes"] = sentLag
+		p.mx[px+"write_lag_bytes"] = writeLagBytes
+		p.mx[px+"flush_lag_bytes"] = flushLagBytes
+		p.mx[px+"replay_lag_bytes"] = replayLagBytes
+		p.mx[px+"write_lag_time"] = writeLagTime
+		p.mx[px+"flush_lag_time"] = flushLagTime
+		p.mx[px+"replay_lag_time"] = replayLagTime
+	})
+	return seen, err
+}
+
+func (p *Postgres) collectReplicationSlots() (map[string]bool, error) {
+	seen := make(map[string]bool)
+
+	var slotName string
+	var active bool
+	var restartRetained, confirmedFlushRetained int64
+
+	err := p.doQuery(p.ctx, queryReplicationSlotFiles, nil, func(column, value string, rowEnd bool) {
+		switch column {
+		case "slot_name":
+			slotName = value
+		case "active":
+			active = value == "t" || value == "true"
+		case "restart_lsn_retained_bytes":
+			restartRetained = parseInt(value)
+		case "confirmed_flush_lsn_retained_bytes":
+			confirmedFlushRetained = parseInt(value)
+		}
+		if !rowEnd {
+			return
+		}
+
+		seen[slotName] = true
+		if !p.seenSlots[slotName] {
+			p.addNewReplicationSlotCharts(slotName)
+		}
+
+		px := "replication_slot_" + slotName + "_"
+		p.mx[px+"restart_lsn_retained_bytes"] = restartRetained
+		p.mx[px+"confirmed_flush_lsn_retained_bytes"] = confirmedFlushRetained
+		p.mx[px+"active"] = boolToInt(active)
+	})
+	return seen, err
+}
+
+func boolToInt(v bool) int64 {
+	if v {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,14 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package postgres
+
+import "testing"
+
+func TestBoolToInt(t *testing.T) {
+	if got := boolToInt(true); got != 1 {
+		t.Errorf("boolToInt(true) = %d, want 1", got)
+	}
+	if got := boolToInt(false); got != 0 {
+		t.Errorf("boolToInt(false) = %d, want 0", got)
+	}
+}
@@ -0,0 +1,174 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package postgres
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const queryPgStatStatementsExtensionExists = `
+SELECT EXISTS (
+    SELECT 1 FROM pg_extension WHERE extname = 'pg_stat_statements'
+) AS enabled
+`
+
+var statementOrderByColumns = map[string]string{
+	"total_time": "total_exec_time",
+	"mean_time":  "mean_exec_time",
+	"calls":      "calls",
+	"rows":       "rows",
+}
+
+func queryTopStatements(orderBy string, maxQueries int) string {
+	col, ok := statementOrderByColumns[orderBy]
+	if !ok {
+		col = statementOrderByColumns["total_time"]
+	}
+	return fmt.Sprintf(`
+SELECT
+    queryid,
+    userid,
+    dbid,
+    query,
+    calls,
+    total_exec_time,
+    mean_exec_time,
+    rows,
+    shared_blks_hit,
+    shared_blks_read
+FROM pg_stat_statements
+ORDER BY %s DESC
+LIMIT %d
+`, col, maxQueries)
+}
+
+var statementWhitespaceRE = regexp.MustCompile(`\s+`)
+
+const statementFingerprintMaxLen = 128
+
+// normalizedQueryCache stabilizes the 'query' chart label across restarts:
+// pg_stat_statements truncates/rewrites literals already, we only need to
+// collapse whitespace and cap the length so the label stays readable.
+type normalizedQueryCache struct {
+	cache map[string]string
+}
+
+func newNormalizedQueryCache() *normalizedQueryCache {
+	return &normalizedQueryCache{cache: make(map[string]string)}
+}
+
+func (c *normalizedQueryCache) fingerprint(queryID, query string) string {
+	if v, ok := c.cache[queryID]; ok {
+		return v
+	}
+	v := normalizeQuery(query)
+	c.cache[queryID] = v
+	return v
+}
+
+func (c *normalizedQueryCache) purge(seen map[string]bool) {
+	for queryID := range c.cache {
+		if !seen[queryID] {
+			delete(c.cache, queryID)
+		}
+	}
+}
+
+func normalizeQuery(query string) string {
+	q := statementWhitespaceRE.ReplaceAllString(strings.TrimSpace(query), " ")
+	if len(q) > statementFingerprintMaxLen {
+		q = q[:statementFingerprintMaxLen]
+	}
+	return q
+}
+
+func (p *Postgres) collectStatements() error {
+	if !p.statStatementsChecked {
+		enabled, err := p.hasPgStatStatementsExtension()
+		if err != nil {
+			return err
+		}
+		p.hasStatStatements = enabled
+		p.statStatementsChecked = true
+	}
+	if !p.hasStatStatements {
+		return nil
+	}
+
+	if p.queryFingerprints == nil {
+		p.queryFingerprints = newNormalizedQueryCache()
+	}
+
+	seen := make(map[string]bool)
+
+	var queryID, userID, dbID, query string
+	var calls, totalTime, meanTime, rows, sharedBlksHit, sharedBlksRead int64
+
+	q := queryTopStatements(p.StatementsOrderBy, p.Config.maxQueries())
+	err := p.doQuery(p.ctx, q, nil, func(column, value string, rowEnd bool) {
+		switch column {
+		case "queryid":
+			queryID = value
+		case "userid":
+			userID = value
+		case "dbid":
+			dbID = value
+		case "query":
+			query = value
+		case "calls":
+			calls = parseInt(value)
+		case "total_exec_time":
+			totalTime = parseInt(value)
+		case "mean_exec_time":
+			meanTime = parseInt(value)
+		case "rows":
+			rows = parseInt(value)
+		case "shared_blks_hit":
+			sharedBlksHit = parseInt(value)
+		case "shared_blks_read":
+			sharedBlksRead = parseInt(value)
+		}
+		if !rowEnd {
+			return
+		}
+
+		seen[queryID] = true
+		fp := p.queryFingerprints.fingerprint(queryID, query)
+		if !p.seenStatements[queryID] {
+			p.addNewStatementCharts(queryID, userID, dbID, fp)
+		}
+
+		px := "statement_" + queryID + "_"
+		p.mx[px+"total_time"] = totalTime
+		p.mx[px+"mean_time"] = meanTime
+		p.mx[px+"calls"] = calls
+		p.mx[px+"rows"] = rows
+		p.mx[px+"shared_blks_hit"] = sharedBlksHit
+		p.mx[px+"shared_blks_read"] = sharedBlksRead
+	})
+	if err != nil {
+		return err
+	}
+
+	for queryID := range p.seenStatements {
+		if !seen[queryID] {
+			p.removeStatementCharts(queryID)
+		}
+	}
+	p.seenStatements = seen
+	p.queryFingerprints.purge(seen)
+
+	return nil
+}
+
+func (p *Postgres) hasPgStatStatementsExtension() (bool, error) {
+	var enabled bool
+	err := p.doQuery(p.ctx, queryPgStatStatementsExtensionExists, nil, func(column, value string, rowEnd bool) {
+		if column == "enabled" {
+			enabled = value == "t" || value == "true"
+		}
+	})
+	return enabled, err
+}
@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package postgres
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeQuery(t *testing.T) {
+	tests := map[string]struct {
+		query string
+		want  string
+	}{
+		"collapses whitespace": {
+			query: "SELECT  *\nFROM   users",
+			want:  "SELECT * FROM users",
+		},
+		"trims surrounding whitespace": {
+			query: "  SELECT 1  ",
+			want:  "SELECT 1",
+		},
+		"caps length": {
+			query: strings.Repeat("a", statementFingerprintMaxLen+50),
+			want:  strings.Repeat("a", statementFingerprintMaxLen),
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := normalizeQuery(tc.query); got != tc.want {
+				t.Errorf("normalizeQuery(%q) = %q, want %q", tc.query, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNormalizedQueryCache(t *testing.T) {
+	c := newNormalizedQueryCache()
+
+	got := c.fingerprint("1", "SELECT  1")
+	if want := "SELECT 1"; got != want {
+		t.Errorf("fingerprint() = %q, want %q", got, want)
+	}
+
+	// a later call with a different query string but the same queryID
+	// returns the cached fingerprint, keeping the chart label stable.
+	if got := c.fingerprint("1", "SELECT  2"); got != "SELECT 1" {
+		t.Errorf("fingerprint() changed for a cached queryID: got %q", got)
+	}
+
+	c.purge(map[string]bool{})
+	if got := c.fingerprint("1", "SELECT  2"); got != "SELECT 2" {
+		t.Errorf("fingerprint() after purge = %q, want %q", got, "SELECT 2")
+	}
+}
+
+func TestQueryTopStatements(t *testing.T) {
+	if q := queryTopStatements("calls", 5); !strings.Contains(q, "ORDER BY calls DESC") || !strings.Contains(q, "LIMIT 5") {
+		t.Errorf("queryTopStatements(calls, 5) did not order by calls or limit 5: %s", q)
+	}
+	if q := queryTopStatements("unknown", 5); !strings.Contains(q, "ORDER BY total_exec_time DESC") {
+		t.Errorf("queryTopStatements(unknown, 5) did not fall back to total_exec_time: %s", q)
+	}
+}
@@ -0,0 +1,346 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package postgres
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+const queryBloatEstimate = `
+SELECT
+    current_database()                                                 AS datname,
+    schemaname,
+    tablename AS relname,
+    ROUND(CASE WHEN otta = 0 THEN 0.0 ELSE sml.relpages::numeric / otta END, 2) AS bloat_ratio
+FROM (
+    SELECT
+        schemaname, tablename, cc.reltuples, cc.relpages, bs,
+        CEIL((cc.reltuples * ((datahdr + ma - (CASE WHEN datahdr % ma = 0 THEN ma ELSE datahdr % ma END)) + nullhdr2 + 4)) / (bs - 20::float)) AS otta
+    FROM (
+        SELECT
+            ma, bs, schemaname, tablename,
+            (datawidth + (hdr + ma - (CASE WHEN hdr % ma = 0 THEN ma ELSE hdr % ma END)))::numeric AS datahdr,
+            (maxfracsum * (nullhdr + ma - (CASE WHEN nullhdr % ma = 0 THEN ma ELSE nullhdr % ma END))) AS nullhdr2
+        FROM (
+            SELECT
+                schemaname, tablename, hdr, ma, bs,
+                SUM((1 - null_frac) * avg_width) AS datawidth,
+                MAX(null_frac) AS maxfracsum,
+                hdr + (SELECT 1 + COUNT(*) / 8 FROM pg_stats s2 WHERE null_frac <> 0 AND s2.schemaname = s.schemaname AND s2.tablename = s.tablename) AS nullhdr
+            FROM pg_stats s, (SELECT 23 AS hdr, 8 AS ma, current_setting('block_size')::numeric AS bs) AS constants
+            WHERE s.schemaname ~ $1 AND s.tablename ~ $2
+            GROUP BY 1, 2, 3, 4, 5
+        ) AS foo
+    ) AS rs
+    JOIN pg_class cc ON cc.relname = rs.tablename
+    JOIN pg_namespace nn ON cc.relnamespace = nn.oid AND nn.nspname = rs.schemaname
+) AS sml
+`
+
+const queryUserTableStats = `
+SELECT
+    current_database() AS datname,
+    schemaname,
+    relname,
+    seq_scan, idx_scan,
+    n_live_tup, n_dead_tup,
+    n_tup_ins, n_tup_upd, n_tup_del, n_tup_hot_upd,
+    COALESCE(EXTRACT(EPOCH FROM (now() - last_autovacuum)), -1) AS last_autovacuum_ago
+FROM pg_stat_user_tables
+WHERE schemaname ~ $1 AND relname ~ $2
+`
+
+const queryUserTableIOStats = `
+SELECT
+    current_database() AS datname,
+    schemaname,
+    relname,
+    heap_blks_read, heap_blks_hit
+FROM pg_statio_user_tables
+WHERE schemaname ~ $1 AND relname ~ $2
+`
+
+const queryUserIndexStats = `
+SELECT
+    current_database() AS datname,
+    schemaname,
+    relname,
+    indexrelname,
+    idx_scan
+FROM pg_stat_user_indexes
+WHERE schemaname ~ $1 AND relname ~ $2
+`
+
+const queryUserIndexIOStats = `
+SELECT
+    current_database() AS datname,
+    schemaname,
+    relname,
+    indexrelname,
+    idx_blks_read, idx_blks_hit
+FROM pg_statio_user_indexes
+WHERE schemaname ~ $1 AND relname ~ $2
+`
+
+func tableSelectorArgs(cfg Config) []any {
+	schemaMatch, tableMatch := cfg.SchemaSelector, cfg.TableSelector
+	if schemaMatch == "" {
+		schemaMatch = ".*"
+	}
+	if tableMatch == "" {
+		tableMatch = ".*"
+	}
+	return []any{schemaMatch, tableMatch}
+}
+
+func tableDimPrefix(dbname, schema, table string) string {
+	return fmt.Sprintf("db_%s_schema_%s_table_%s_", dbname, schema, table)
+}
+
+func indexDimPrefix(dbname, schema, table, index string) string {
+	return fmt.Sprintf("db_%s_schema_%s_table_%s_index_%s_", dbname, schema, table, index)
+}
+
+func (p *Postgres) collectUserTables() error {
+	args := tableSelectorArgs(p.Config)
+
+	seenTables, err := p.collectUserTableStats(args)
+	if err != nil {
+		return err
+	}
+	if err := p.collectUserTableIOStats(args); err != nil {
+		return err
+	}
+	if p.CollectBloatSize {
+		if err := p.collectTableBloat(args); err != nil {
+			return err
+		}
+	}
+
+	seenIndexes, err := p.collectUserIndexStats(args)
+	if err != nil {
+		return err
+	}
+	if err := p.collectUserIndexIOStats(args); err != nil {
+		return err
+	}
+
+	for key := range p.seenTables {
+		if !seenTables[key] {
+			db, schema, table := splitKey3(key)
+			p.removeTableCharts(db, schema, table)
+		}
+	}
+	p.seenTables = seenTables
+
+	for key := range p.seenIndexes {
+		if !seenIndexes[key] {
+			db, schema, table, index := splitKey4(key)
+			p.removeIndexCharts(db, schema, table, index)
+		}
+	}
+	p.seenIndexes = seenIndexes
+
+	return nil
+}
+
+func (p *Postgres) collectUserTableStats(args []any) (map[string]bool, error) {
+	seen := make(map[string]bool)
+
+	var db, schema, table string
+	var seqScan, idxScan, nLiveTup, nDeadTup, nTupIns, nTupUpd, nTupDel, nTupHotUpd, lastVacAgo int64
+
+	err := p.doQuery(p.ctx, queryUserTableStats, args, func(column, value string, rowEnd bool) {
+		switch column {
+		case "datname":
+			db = value
+		case "schemaname":
+			schema = value
+		case "relname":
+			table = value
+		case "seq_scan":
+			seqScan = parseInt(value)
+		case "idx_scan":
+			idxScan = parseInt(value)
+		case "n_live_tup":
+			nLiveTup = parseInt(value)
+		case "n_dead_tup":
+			nDeadTup = parseInt(value)
+		case "n_tup_ins":
+			nTupIns = parseInt(value)
+		case "n_tup_upd":
+			nTupUpd = parseInt(value)
+		case "n_tup_del":
+			nTupDel = parseInt(value)
+		case "n_tup_hot_upd":
+			nTupHotUpd = parseInt(value)
+		case "last_autovacuum_ago":
+			lastVacAgo = parseInt(value)
+		}
+		if !rowEnd {
+			return
+		}
+
+		key := joinKey(db, schema, table)
+		seen[key] = true
+		if !p.seenTables[key] {
+			p.addNewTableCharts(db, schema, table)
+		}
+
+		px := tableDimPrefix(db, schema, table)
+		p.mx[px+"seq_scan"] = seqScan
+		p.mx[px+"idx_scan"] = idxScan
+		p.mx[px+"n_live_tup"] = nLiveTup
+		p.mx[px+"n_dead_tup"] = nDeadTup
+		p.mx[px+"n_tup_ins"] = nTupIns
+		p.mx[px+"n_tup_upd"] = nTupUpd
+		p.mx[px+"n_tup_del"] = nTupDel
+		p.mx[px+"n_tup_hot_upd_perc"] = hotUpdatePercent(nTupHotUpd, nTupUpd)
+		if lastVacAgo >= 0 {
+			p.mx[px+"last_autovacuum_ago"] = lastVacAgo
+		}
+	})
+	return seen, err
+}
+
+func (p *Postgres) collectUserTableIOStats(args []any) error {
+	var db, schema, table string
+	var heapBlksRead, heapBlksHit int64
+
+	return p.doQuery(p.ctx, queryUserTableIOStats, args, func(column, value string, rowEnd bool) {
+		switch column {
+		case "datname":
+			db = value
+		case "schemaname":
+			schema = value
+		case "relname":
+			table = value
+		case "heap_blks_read":
+			heapBlksRead = parseInt(value)
+		case "heap_blks_hit":
+			heapBlksHit = parseInt(value)
+		}
+		if !rowEnd {
+			return
+		}
+		px := tableDimPrefix(db, schema, table)
+		p.mx[px+"heap_blks_read"] = heapBlksRead
+		p.mx[px+"heap_blks_hit"] = heapBlksHit
+	})
+}
+
+func (p *Postgres) collectTableBloat(args []any) error {
+	var db, schema, table string
+	var bloatRatio float64
+
+	return p.doQuery(p.ctx, queryBloatEstimate, args, func(column, value string, rowEnd bool) {
+		switch column {
+		case "datname":
+			db = value
+		case "schemaname":
+			schema = value
+		case "relname":
+			table = value
+		case "bloat_ratio":
+			bloatRatio = parseFloat(value)
+		}
+		if !rowEnd {
+			return
+		}
+		px := tableDimPrefix(db, schema, table)
+		p.mx[px+"bloat_size_perc"] = bloatRatioToPercent(bloatRatio)
+	})
+}
+
+func (p *Postgres) collectUserIndexStats(args []any) (map[string]bool, error) {
+	seen := make(map[string]bool)
+
+	var db, schema, table, index string
+	var idxScan int64
+
+	err := p.doQuery(p.ctx, queryUserIndexStats, args, func(column, value string, rowEnd bool) {
+		switch column {
+		case "datname":
+			db = value
+		case "schemaname":
+			schema = value
+		case "relname":
+			table = value
+		case "indexrelname":
+			index = value
+		case "idx_scan":
+			idxScan = parseInt(value)
+		}
+		if !rowEnd {
+			return
+		}
+
+		key := joinKey(db, schema, table, index)
+		seen[key] = true
+		if !p.seenIndexes[key] {
+			p.addNewIndexCharts(db, schema, table, index)
+		}
+
+		px := indexDimPrefix(db, schema, table, index)
+		p.mx[px+"idx_scan"] = idxScan
+	})
+	return seen, err
+}
+
+func (p *Postgres) collectUserIndexIOStats(args []any) error {
+	var db, schema, table, index string
+	var idxBlksRead, idxBlksHit int64
+
+	return p.doQuery(p.ctx, queryUserIndexIOStats, args, func(column, value string, rowEnd bool) {
+		switch column {
+		case "datname":
+			db = value
+		case "schemaname":
+			schema = value
+		case "relname":
+			table = value
+		case "indexrelname":
+			index = value
+		case "idx_blks_read":
+			idxBlksRead = parseInt(value)
+		case "idx_blks_hit":
+			idxBlksHit = parseInt(value)
+		}
+		if !rowEnd {
+			return
+		}
+		px := indexDimPrefix(db, schema, table, index)
+		p.mx[px+"idx_blks_read"] = idxBlksRead
+		p.mx[px+"idx_blks_hit"] = idxBlksHit
+	})
+}
+
+func hotUpdatePercent(hotUpd, upd int64) int64 {
+	if upd <= 0 {
+		return 0
+	}
+	return hotUpd * 100 / upd
+}
+
+func bloatRatioToPercent(ratio float64) int64 {
+	if ratio <= 1 {
+		return 0
+	}
+	return int64(math.Round((ratio - 1) * 100))
+}
+
+func joinKey(parts ...string) string {
+	return strings.Join(parts, "|")
+}
+
+func splitKey3(key string) (string, string, string) {
+	p := strings.SplitN(key, "|", 3)
+	return p[0], p[1], p[2]
+}
+
+func splitKey4(key string) (string, string, string, string) {
+	p := strings.SplitN(key, "|", 4)
+	return p[0], p[1], p[2], p[3]
+}
@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package postgres
+
+import "testing"
+
+func TestHotUpdatePercent(t *testing.T) {
+	tests := map[string]struct {
+		hotUpd, upd int64
+		want        int64
+	}{
+		"no updates":     {hotUpd: 0, upd: 0, want: 0},
+		"all hot":        {hotUpd: 10, upd: 10, want: 100},
+		"half hot":       {hotUpd: 5, upd: 10, want: 50},
+		"no hot updates": {hotUpd: 0, upd: 10, want: 0},
+		"negative guard": {hotUpd: 5, upd: -1, want: 0},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := hotUpdatePercent(tc.hotUpd, tc.upd); got != tc.want {
+				t.Errorf("hotUpdatePercent(%d, %d) = %d, want %d", tc.hotUpd, tc.upd, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBloatRatioToPercent(t *testing.T) {
+	tests := map[string]struct {
+		ratio float64
+		want  int64
+	}{
+		"no bloat":   {ratio: 1, want: 0},
+		"below one":  {ratio: 0.8, want: 0},
+		"20% bloat":  {ratio: 1.2, want: 20},
+		"100% bloat": {ratio: 2, want: 100},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := bloatRatioToPercent(tc.ratio); got != tc.want {
+				t.Errorf("bloatRatioToPercent(%v) = %d, want %d", tc.ratio, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestJoinSplitKey(t *testing.T) {
+	key := joinKey("db", "public", "users")
+	db, schema, table := splitKey3(key)
+	if db != "db" || schema != "public" || table != "users" {
+		t.Errorf("splitKey3(%q) = (%q, %q, %q), want (db, public, users)", key, db, schema, table)
+	}
+
+	idxKey := joinKey("db", "public", "users", "users_pkey")
+	db, schema, table, index := splitKey4(idxKey)
+	if db != "db" || schema != "public" || table != "users" || index != "users_pkey" {
+		t.Errorf("splitKey4(%q) = (%q, %q, %q, %q), want (db, public, users, users_pkey)", idxKey, db, schema, table, index)
+	}
+}
@@ -0,0 +1,31 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package postgres
+
+// Config is the postgres module configuration.
+type Config struct {
+	DSN string `yaml:"dsn" json:"dsn"`
+
+	// SchemaSelector and TableSelector bound the cardinality of the
+	// per-table/per-index charts: when non-empty, only schemas/tables whose
+	// name matches the regex are collected.
+	SchemaSelector   string `yaml:"collect_schemas_matching,omitempty" json:"schema_selector"`
+	TableSelector    string `yaml:"collect_tables_matching,omitempty" json:"table_selector"`
+	CollectBloatSize bool   `yaml:"collect_table_bloat_size,omitempty" json:"collect_table_bloat_size"`
+
+	// MaxQueries and StatementsOrderBy control the pg_stat_statements
+	// top-query collection; it only runs when the extension is installed.
+	MaxQueries        int    `yaml:"max_queries,omitempty" json:"max_queries"`
+	StatementsOrderBy string `yaml:"statements_order_by,omitempty" json:"statements_order_by"`
+
+	// ActivityExcludeAppName excludes pg_stat_activity rows whose
+	// application_name matches the regex, e.g. the monitoring agent itself.
+	ActivityExcludeAppName string `yaml:"activity_exclude_app_name,omitempty" json:"activity_exclude_app_name"`
+}
+
+func (c Config) maxQueries() int {
+	if c.MaxQueries <= 0 {
+		return 10
+	}
+	return c.MaxQueries
+}
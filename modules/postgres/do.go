@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+)
+
+// doQuery runs query with args bound as parameters (never interpolated into
+// the SQL string) and streams the result to assign, one (column, value) pair
+// at a time. rowEnd is true for the last column of each row, so callers can
+// flush per-row accumulators.
+func (p *Postgres) doQuery(ctx context.Context, query string, args []any, assign func(column, value string, rowEnd bool)) error {
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	return readRows(rows, assign)
+}
+
+func readRows(rows *sql.Rows, assign func(column, value string, rowEnd bool)) error {
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	values := make([]any, len(columns))
+	for i := range values {
+		values[i] = &sql.NullString{}
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(values...); err != nil {
+			return err
+		}
+		for i, v := range values {
+			assign(columns[i], v.(*sql.NullString).String, i == len(values)-1)
+		}
+	}
+	return rows.Err()
+}
+
+// parseInt best-effort parses a scanned column value (an int64, a float that
+// is truncated toward zero, or empty/NULL) into an int64 metric value.
+func parseInt(value string) int64 {
+	if value == "" {
+		return 0
+	}
+	if v, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return v
+	}
+	if v, err := strconv.ParseFloat(value, 64); err == nil {
+		return int64(v)
+	}
+	return 0
+}
+
+func parseFloat(value string) float64 {
+	v, _ := strconv.ParseFloat(value, 64)
+	return v
+}